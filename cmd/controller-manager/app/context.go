@@ -0,0 +1,30 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+)
+
+// ControllerContext carries the dependencies shared by every sub-controller: the kube
+// client, and an EventRecorder sub-controllers use to emit Warning events on persistent
+// reconcile failures against the objects they reconcile.
+type ControllerContext struct {
+	KubernetesClient kubernetes.Interface
+	EventRecorder    record.EventRecorder
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/leaderelection"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog"
+
+	"kubesphere.io/kubesphere/cmd/controller-manager/app/options"
+	"kubesphere.io/kubesphere/pkg/events"
+	"kubesphere.io/kubesphere/pkg/leaderelector"
+	"kubesphere.io/kubesphere/pkg/simple/client/k8s"
+)
+
+// NewControllerManagerCommand creates the ks-controller-manager command with its flags.
+func NewControllerManagerCommand() *cobra.Command {
+	s := options.NewKubeSphereControllerManagerOptions()
+
+	cmd := &cobra.Command{
+		Use:  "controller-manager",
+		Long: `KubeSphere controller manager is a daemon that embeds the control loops shipped with KubeSphere.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if errs := s.Validate(); len(errs) != 0 {
+				return utilerrors.NewAggregate(errs)
+			}
+			return Run(s, SetupSignalContext())
+		},
+	}
+
+	fss := s.Flags()
+	fs := cmd.Flags()
+	for _, f := range fss.FlagSets {
+		fs.AddFlagSet(f)
+	}
+
+	cliflag.SetUsageAndHelpFunc(cmd, fss, 80)
+
+	return cmd
+}
+
+// Run runs the controller manager, blocking until ctx is cancelled or leader election fails.
+func Run(s *options.KubeSphereControllerManagerOptions, ctx context.Context) error {
+	kubernetesClient, err := k8s.NewKubernetesClient(s.KubernetesOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %s", err)
+	}
+
+	enabled, disabled := splitControllers(s)
+	klog.Infof("enabled controllers: %v", enabled)
+	klog.Infof("disabled controllers: %v", disabled)
+
+	broadcaster := events.NewBroadcaster(kubernetesClient.Kubernetes(), s.EventBroadcasterQPS, s.EventBroadcasterBurst)
+	go func() {
+		<-ctx.Done()
+		broadcaster.Shutdown()
+	}()
+	recorder := events.NewRecorder(broadcaster)
+
+	controllerCtx := &ControllerContext{
+		KubernetesClient: kubernetesClient.Kubernetes(),
+		EventRecorder:    recorder,
+	}
+
+	ready := &readyState{}
+	var healthzAdaptor *leaderelection.HealthzAdaptor
+	if s.LeaderElect {
+		healthzAdaptor = leaderelection.NewLeaderHealthzAdaptor(s.HealthzTimeout)
+	}
+	go serveHealthz(ctx, s.HealthzBindAddress, healthzAdaptor, ready)
+
+	if !s.LeaderElect {
+		return run(ctx, controllerCtx, ready, enabled)
+	}
+
+	identity := string(uuid.NewUUID())
+	lock, err := s.NewResourceLock(kubernetesClient.Kubernetes(), identity, recorder)
+	if err != nil {
+		return fmt.Errorf("error creating leader election lock: %s", err)
+	}
+	s.LeaderElection.Lock = lock
+	s.LeaderElection.WatchDog = healthzAdaptor
+
+	elector := leaderelector.NewElector(*s.LeaderElection, s.LeaderElectionRenewDeadlineRetryDuration,
+		func(leaderCtx context.Context) {
+			if err := run(leaderCtx, controllerCtx, ready, enabled); err != nil {
+				klog.Errorf("controller manager exited: %s", err)
+			}
+		},
+		func() {
+			ready.set(false)
+			klog.Info("leader election lost, pausing reconciliation")
+		})
+
+	return elector.Run(ctx)
+}
+
+// run starts every controller named in enabledControllers and blocks until ctx is
+// cancelled. It marks ready as soon as it has launched a goroutine for each of them;
+// it does not wait for a setup func to report that its controller has started or
+// that any informer cache has synced. A controller not present in enabledControllers
+// is never started, so its dependencies are never even constructed.
+func run(ctx context.Context, controllerCtx *ControllerContext, ready *readyState, enabledControllers []string) error {
+	var wg sync.WaitGroup
+	for _, name := range enabledControllers {
+		setup, ok := controllerSetupFuncs[name]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, setup controllerSetupFunc) {
+			defer wg.Done()
+			if err := setup(ctx, controllerCtx); err != nil {
+				klog.Errorf("controller %q exited: %s", name, err)
+			}
+		}(name, setup)
+	}
+
+	ready.set(true)
+	<-ctx.Done()
+	wg.Wait()
+	return nil
+}
+
+// splitControllers partitions options.KnownControllers() into the names enabled and
+// disabled by s.Controllers.
+func splitControllers(s *options.KubeSphereControllerManagerOptions) (enabled, disabled []string) {
+	disabledByDefault := options.ControllersDisabledByDefault()
+	for _, name := range options.KnownControllers() {
+		if options.IsControllerEnabled(name, disabledByDefault, s.Controllers) {
+			enabled = append(enabled, name)
+		} else {
+			disabled = append(disabled, name)
+		}
+	}
+	return enabled, disabled
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+
+	"k8s.io/klog"
+)
+
+// controllerSetupFunc starts one controller's reconciliation loop. Like run() for the
+// manager as a whole, it is expected to block until ctx is cancelled.
+type controllerSetupFunc func(ctx context.Context, controllerCtx *ControllerContext) error
+
+// controllerSetupFuncs maps each name in options.KnownControllers() to the function that
+// starts it. A controller not selected by --controllers never has its setup func invoked
+// at all, so operators running in minimal mode can drop its dependencies entirely instead
+// of merely disabling its reconciliation.
+//
+// The individual reconcilers behind these names don't exist in this tree yet, so each is
+// wired to a placeholder that logs and idles until ctx is cancelled; swap each entry for
+// the real Start func as its controller lands.
+var controllerSetupFuncs = map[string]controllerSetupFunc{
+	"devops":       placeholderController("devops"),
+	"openpitrix":   placeholderController("openpitrix"),
+	"servicemesh":  placeholderController("servicemesh"),
+	"network":      placeholderController("network"),
+	"gateway":      placeholderController("gateway"),
+	"multicluster": placeholderController("multicluster"),
+	"application":  placeholderController("application"),
+	"user":         placeholderController("user"),
+	"workspace":    placeholderController("workspace"),
+}
+
+func placeholderController(name string) controllerSetupFunc {
+	return func(ctx context.Context, controllerCtx *ControllerContext) error {
+		klog.Infof("starting controller %q", name)
+		<-ctx.Done()
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/component-base/metrics/legacyregistry"
+	// registers the standard client-go leader election metrics, including the
+	// leader_election_master_status gauge, with component-base's legacy registry,
+	// which is what /metrics serves.
+	_ "k8s.io/component-base/metrics/prometheus/clientgo/leaderelection"
+	"k8s.io/klog"
+)
+
+// readyState is the flag behind the /readyz handler. run sets it once it has launched
+// a goroutine for every enabled controller, without waiting for informer caches to
+// sync; it is cleared when leadership is lost.
+type readyState struct {
+	ready int32
+}
+
+func (r *readyState) set(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&r.ready, v)
+}
+
+func (r *readyState) isReady() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+// serveHealthz starts an HTTP server exposing /healthz, /readyz and /metrics on
+// bindAddress and blocks until ctx is cancelled. It is a no-op if bindAddress is empty.
+//
+// /healthz reports unhealthy once healthzAdaptor observes the leader has gone too
+// long without renewing its lease, so kubelet can kill a wedged leader that isn't
+// yielding. /readyz reports ready once ready.isReady() returns true.
+func serveHealthz(ctx context.Context, bindAddress string, healthzAdaptor *leaderelection.HealthzAdaptor, ready *readyState) {
+	if bindAddress == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		// healthzAdaptor is nil when leader election is disabled; there's no lease to
+		// go stale, so the process is healthy as long as it's able to serve this request.
+		if healthzAdaptor != nil {
+			if err := healthzAdaptor.Check(r); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !ready.isReady() {
+			http.Error(w, "controllers not started", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/metrics", legacyregistry.Handler())
+
+	server := &http.Server{Addr: bindAddress, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	klog.Infof("serving healthz, readyz and metrics on %s", bindAddress)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		klog.Errorf("healthz server exited: %s", err)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import "k8s.io/apimachinery/pkg/util/sets"
+
+// knownControllers are the reconcilers the controller manager is able to register,
+// named the way they're referenced by the --controllers flag.
+var knownControllers = []string{
+	"devops",
+	"openpitrix",
+	"servicemesh",
+	"network",
+	"gateway",
+	"multicluster",
+	"application",
+	"user",
+	"workspace",
+}
+
+// KnownControllers returns the names of every controller the controller manager
+// is able to register.
+func KnownControllers() []string {
+	return append([]string(nil), knownControllers...)
+}
+
+// ControllersDisabledByDefault returns the set of controllers that are not started
+// unless explicitly named in --controllers, even when --controllers=* is set.
+func ControllersDisabledByDefault() sets.String {
+	return sets.NewString()
+}
+
+// IsControllerEnabled follows the same --controllers semantics as kube-controller-manager:
+// an entry "foo" enables controller foo, "-foo" disables it, and "*" enables every
+// controller not in disabledByDefault. A more specific entry always wins over "*".
+func IsControllerEnabled(name string, disabledByDefault sets.String, controllers []string) bool {
+	hasStar := false
+	for _, ctrl := range controllers {
+		if ctrl == name {
+			return true
+		}
+		if ctrl == "-"+name {
+			return false
+		}
+		if ctrl == "*" {
+			hasStar = true
+		}
+	}
+
+	if !hasStar {
+		return false
+	}
+
+	return !disabledByDefault.Has(name)
+}
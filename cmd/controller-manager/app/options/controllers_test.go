@@ -0,0 +1,104 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestIsControllerEnabled(t *testing.T) {
+	tests := []struct {
+		name              string
+		controllerName    string
+		disabledByDefault sets.String
+		controllers       []string
+		want              bool
+	}{
+		{
+			name:           "explicitly named is enabled",
+			controllerName: "bar",
+			controllers:    []string{"foo", "bar", "-baz"},
+			want:           true,
+		},
+		{
+			name:           "explicitly excluded is disabled",
+			controllerName: "baz",
+			controllers:    []string{"foo", "bar", "-baz"},
+			want:           false,
+		},
+		{
+			name:           "absent from an explicit list is disabled",
+			controllerName: "quux",
+			controllers:    []string{"foo", "bar"},
+			want:           false,
+		},
+		{
+			name:           "star enables everything not disabled by default",
+			controllerName: "bar",
+			controllers:    []string{"*"},
+			want:           true,
+		},
+		{
+			name:           "star with an explicit exclusion is disabled",
+			controllerName: "bar",
+			controllers:    []string{"*", "-bar"},
+			want:           false,
+		},
+		{
+			name:              "star does not enable a controller disabled by default",
+			controllerName:    "bar",
+			disabledByDefault: sets.NewString("bar"),
+			controllers:       []string{"*"},
+			want:              false,
+		},
+		{
+			name:              "an explicit name overrides disabled-by-default under star",
+			controllerName:    "bar",
+			disabledByDefault: sets.NewString("bar"),
+			controllers:       []string{"*", "bar"},
+			want:              true,
+		},
+		{
+			name:              "an explicit exclusion overrides disabled-by-default under star",
+			controllerName:    "bar",
+			disabledByDefault: sets.NewString("bar"),
+			controllers:       []string{"*", "-bar"},
+			want:              false,
+		},
+		{
+			name:           "empty controllers list disables everything",
+			controllerName: "bar",
+			controllers:    nil,
+			want:           false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disabledByDefault := tt.disabledByDefault
+			if disabledByDefault == nil {
+				disabledByDefault = sets.NewString()
+			}
+			if got := IsControllerEnabled(tt.controllerName, disabledByDefault, tt.controllers); got != tt.want {
+				t.Errorf("IsControllerEnabled(%q, %v, %v) = %v, want %v",
+					tt.controllerName, disabledByDefault, tt.controllers, got, tt.want)
+			}
+		})
+	}
+}
@@ -18,15 +18,20 @@ package options
 
 import (
 	"flag"
+	"fmt"
 	"strings"
 	"time"
 
 	"kubesphere.io/kubesphere/pkg/apiserver/authentication"
 
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog"
 
@@ -41,6 +46,15 @@ import (
 	"kubesphere.io/kubesphere/pkg/simple/client/servicemesh"
 )
 
+// supportedLeaderElectionResourceLocks are the resource lock types that resourcelock.New accepts.
+var supportedLeaderElectionResourceLocks = sets.NewString(
+	resourcelock.LeasesResourceLock,
+	resourcelock.ConfigMapsResourceLock,
+	resourcelock.EndpointsResourceLock,
+	resourcelock.ConfigMapsLeasesResourceLock,
+	resourcelock.EndpointsLeasesResourceLock,
+)
+
 type KubeSphereControllerManagerOptions struct {
 	KubernetesOptions     *k8s.KubernetesOptions
 	DevopsOptions         *jenkins.Options
@@ -54,7 +68,33 @@ type KubeSphereControllerManagerOptions struct {
 	GatewayOptions        *gateway.Options
 	LeaderElect           bool
 	LeaderElection        *leaderelection.LeaderElectionConfig
-	WebhookCertDir        string
+	// LeaderElectionResourceLock determines which resource lock to use for leader election,
+	// one of "leases", "configmaps", "endpoints", "configmapsleases", "endpointsleases".
+	LeaderElectionResourceLock string
+	// LeaderElectionResourceName is the name of the resource that leader election will use for holding the leader lock.
+	LeaderElectionResourceName string
+	// LeaderElectionResourceNamespace is the namespace of the resource that leader election will use for holding the leader lock.
+	LeaderElectionResourceNamespace string
+	// LeaderElectionRenewDeadlineRetryDuration bounds how long the controller manager keeps
+	// retrying re-acquisition of leadership after a lease renewal is lost before it exits.
+	// During this window reconciliation is paused but caches are kept warm, so a brief
+	// apiserver blip doesn't force a process restart.
+	LeaderElectionRenewDeadlineRetryDuration time.Duration
+	// HealthzBindAddress is the TCP address on which to serve /healthz, /readyz and /metrics.
+	// An empty value disables the healthz server.
+	HealthzBindAddress string
+	// HealthzTimeout is added to the leader election lease duration when deciding whether the
+	// leader has gone too long without renewing its lease to still be considered healthy.
+	HealthzTimeout time.Duration
+	WebhookCertDir string
+	// Controllers is the effective set of controllers to run, following kube-controller-manager's
+	// "*", "foo", "-foo" semantics. See IsControllerEnabled.
+	Controllers []string
+	// EventBroadcasterQPS and EventBroadcasterBurst bound how fast the controller manager's
+	// event recorder may post Events about any one involved object to the apiserver. Each
+	// object has its own limit, so the total rate across objects is not bounded.
+	EventBroadcasterQPS   float32
+	EventBroadcasterBurst int
 
 	// KubeSphere is using sigs.k8s.io/application as fundamental object to implement Application Management.
 	// There are other projects also built on sigs.k8s.io/application, when KubeSphere installed along side
@@ -83,9 +123,18 @@ func NewKubeSphereControllerManagerOptions() *KubeSphereControllerManagerOptions
 			RenewDeadline: 15 * time.Second,
 			RetryPeriod:   5 * time.Second,
 		},
-		LeaderElect:         false,
-		WebhookCertDir:      "",
-		ApplicationSelector: "",
+		LeaderElect:                              false,
+		LeaderElectionResourceLock:               resourcelock.LeasesResourceLock,
+		LeaderElectionResourceName:               "ks-controller-manager",
+		LeaderElectionResourceNamespace:          "kubesphere-system",
+		LeaderElectionRenewDeadlineRetryDuration: 30 * time.Second,
+		HealthzBindAddress:                       ":8081",
+		HealthzTimeout:                           20 * time.Second,
+		WebhookCertDir:                           "",
+		Controllers:                              []string{"*"},
+		EventBroadcasterQPS:                      16,
+		EventBroadcasterBurst:                    100,
+		ApplicationSelector:                      "",
 	}
 
 	return s
@@ -111,6 +160,28 @@ func (s *KubeSphereControllerManagerOptions) Flags() cliflag.NamedFlagSets {
 		"Whether to enable leader election. This field should be enabled when controller manager"+
 		"deployed with multiple replicas.")
 
+	fs.StringVar(&s.LeaderElectionResourceLock, "leader-elect-resource-lock", s.LeaderElectionResourceLock, ""+
+		"The type of resource object that is used for locking during leader election. "+
+		"Supported options are 'leases', 'configmaps', 'endpoints', 'configmapsleases' and 'endpointsleases'.")
+
+	fs.StringVar(&s.LeaderElectionResourceName, "leader-elect-resource-name", s.LeaderElectionResourceName, ""+
+		"The name of resource object that is used for locking during leader election.")
+
+	fs.StringVar(&s.LeaderElectionResourceNamespace, "leader-elect-resource-namespace", s.LeaderElectionResourceNamespace, ""+
+		"The namespace of resource object that is used for locking during leader election.")
+
+	fs.DurationVar(&s.LeaderElectionRenewDeadlineRetryDuration, "leader-elect-renew-deadline-retry-duration", s.LeaderElectionRenewDeadlineRetryDuration, ""+
+		"How long the controller manager keeps retrying to re-acquire leadership after a lease "+
+		"renewal is lost before it exits. Reconciliation is paused but caches are kept warm for "+
+		"the duration of the retry, so a brief apiserver blip doesn't force a process restart.")
+
+	fs.StringVar(&s.HealthzBindAddress, "healthz-bind-address", s.HealthzBindAddress, ""+
+		"The TCP address on which to serve /healthz, /readyz and /metrics. An empty value disables the healthz server.")
+
+	fs.DurationVar(&s.HealthzTimeout, "healthz-timeout", s.HealthzTimeout, ""+
+		"The duration added to the leader election lease duration when deciding whether the leader "+
+		"has gone too long without renewing its lease to still be considered healthy.")
+
 	fs.StringVar(&s.WebhookCertDir, "webhook-cert-dir", s.WebhookCertDir, ""+
 		"Certificate directory used to setup webhooks, need tls.crt and tls.key placed inside."+
 		"if not set, webhook server would look up the server key and certificate in"+
@@ -121,6 +192,19 @@ func (s *KubeSphereControllerManagerOptions) Flags() cliflag.NamedFlagSets {
 		"Only reconcile application(sigs.k8s.io/application) objects match given selector, this could avoid conflicts with "+
 		"other projects built on top of sig-application. Default behavior is to reconcile all of application objects.")
 
+	gfs.StringSliceVar(&s.Controllers, "controllers", s.Controllers, fmt.Sprintf(""+
+		"A list of controllers to enable. '*' enables all on-by-default controllers, 'foo' enables "+
+		"the controller named 'foo', '-foo' disables the controller named 'foo'. All known controllers: %s",
+		strings.Join(KnownControllers(), ", ")))
+
+	gfs.Float32Var(&s.EventBroadcasterQPS, "event-broadcaster-qps", s.EventBroadcasterQPS, ""+
+		"The maximum number of Events per second the controller manager's event recorder may post to the apiserver "+
+		"about any one involved object. The limit applies per object, not to the total across objects.")
+
+	gfs.IntVar(&s.EventBroadcasterBurst, "event-broadcaster-burst", s.EventBroadcasterBurst, ""+
+		"The maximum burst of Events the controller manager's event recorder may post to the apiserver "+
+		"about any one involved object, temporarily allowing it to exceed event-broadcaster-qps.")
+
 	kfs := fss.FlagSet("klog")
 	local := flag.NewFlagSet("klog", flag.ExitOnError)
 	klog.InitFlags(local)
@@ -134,13 +218,32 @@ func (s *KubeSphereControllerManagerOptions) Flags() cliflag.NamedFlagSets {
 
 func (s *KubeSphereControllerManagerOptions) Validate() []error {
 	var errs []error
-	errs = append(errs, s.DevopsOptions.Validate()...)
+	disabledByDefault := ControllersDisabledByDefault()
+
+	if IsControllerEnabled("devops", disabledByDefault, s.Controllers) {
+		errs = append(errs, s.DevopsOptions.Validate()...)
+	}
 	errs = append(errs, s.KubernetesOptions.Validate()...)
 	errs = append(errs, s.S3Options.Validate()...)
-	errs = append(errs, s.OpenPitrixOptions.Validate()...)
-	errs = append(errs, s.NetworkOptions.Validate()...)
+	if IsControllerEnabled("openpitrix", disabledByDefault, s.Controllers) {
+		errs = append(errs, s.OpenPitrixOptions.Validate()...)
+	}
+	if IsControllerEnabled("network", disabledByDefault, s.Controllers) {
+		errs = append(errs, s.NetworkOptions.Validate()...)
+	}
 	errs = append(errs, s.LdapOptions.Validate()...)
-	errs = append(errs, s.MultiClusterOptions.Validate()...)
+	if IsControllerEnabled("multicluster", disabledByDefault, s.Controllers) {
+		errs = append(errs, s.MultiClusterOptions.Validate()...)
+	}
+
+	if !supportedLeaderElectionResourceLocks.Has(s.LeaderElectionResourceLock) {
+		errs = append(errs, fmt.Errorf("unsupported leader-elect-resource-lock: %s, must be one of %v",
+			s.LeaderElectionResourceLock, supportedLeaderElectionResourceLocks.List()))
+	}
+
+	if errMsgs := utilvalidation.IsDNS1123Subdomain(s.LeaderElectionResourceName); len(errMsgs) != 0 {
+		errs = append(errs, fmt.Errorf("invalid leader-elect-resource-name %q: %s", s.LeaderElectionResourceName, strings.Join(errMsgs, ", ")))
+	}
 
 	if len(s.ApplicationSelector) != 0 {
 		_, err := labels.Parse(s.ApplicationSelector)
@@ -152,6 +255,21 @@ func (s *KubeSphereControllerManagerOptions) Validate() []error {
 	return errs
 }
 
+// NewResourceLock builds the resourcelock.Interface described by LeaderElectionResourceLock,
+// LeaderElectionResourceName and LeaderElectionResourceNamespace, for use as the Lock of a
+// leaderelection.LeaderElectionConfig.
+func (s *KubeSphereControllerManagerOptions) NewResourceLock(kubeClient kubernetes.Interface, identity string, eventRecorder resourcelock.EventRecorder) (resourcelock.Interface, error) {
+	return resourcelock.New(s.LeaderElectionResourceLock,
+		s.LeaderElectionResourceNamespace,
+		s.LeaderElectionResourceName,
+		kubeClient.CoreV1(),
+		kubeClient.CoordinationV1(),
+		resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: eventRecorder,
+		})
+}
+
 func (s *KubeSphereControllerManagerOptions) bindLeaderElectionFlags(l *leaderelection.LeaderElectionConfig, fs *pflag.FlagSet) {
 	fs.DurationVar(&l.LeaseDuration, "leader-elect-lease-duration", l.LeaseDuration, ""+
 		"The duration that non-leader candidates will wait after observing a leadership "+
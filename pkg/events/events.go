@@ -0,0 +1,60 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events builds the record.EventBroadcaster and record.EventRecorder shared by the
+// controller manager and its sub-controllers, so that leader election transitions and
+// persistent reconcile failures show up as Kubernetes Events instead of log-only output.
+package events
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	// ksscheme registers every KubeSphere CRD type alongside the built-in ones, so
+	// sub-controllers reconciling KubeSphere objects (workspaces, users, applications,
+	// devops/openpitrix resources, ...) can still resolve an object reference and get
+	// a real Event instead of a silently dropped one.
+	ksscheme "kubesphere.io/kubesphere/pkg/client/clientset/versioned/scheme"
+)
+
+// SourceComponent is the EventSource.Component recorded against every Event the
+// controller manager emits.
+const SourceComponent = "kubesphere-controller-manager"
+
+// NewBroadcaster builds a record.EventBroadcaster bound to kubeClient. Events are logged
+// via klog and recorded to the apiserver. qps/burst drive the broadcaster's spam filter,
+// which rate-limits Events per source and involved object; the total rate across
+// objects is not bounded. Callers must Shutdown() the broadcaster when done.
+func NewBroadcaster(kubeClient kubernetes.Interface, qps float32, burst int) record.EventBroadcaster {
+	broadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+		QPS:       qps,
+		BurstSize: burst,
+	})
+	broadcaster.StartLogging(klog.V(4).Infof)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	return broadcaster
+}
+
+// NewRecorder returns an EventRecorder sourced as SourceComponent, for use both in the
+// leader election resource lock and in each sub-controller's ControllerContext. It is
+// built off ksscheme.Scheme rather than the bare client-go scheme so that Event/Eventf
+// can resolve an object reference for KubeSphere CRDs, not just built-in core types.
+func NewRecorder(broadcaster record.EventBroadcaster) record.EventRecorder {
+	return broadcaster.NewRecorder(ksscheme.Scheme, corev1.EventSource{Component: SourceComponent})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leaderelector wraps client-go's leaderelection package so that a
+// lost lease renewal does not have to be fatal. Plain leaderelection.RunOrDie
+// returns as soon as OnStoppedLeading fires, which callers usually turn into
+// os.Exit. Elector instead gives the process a bounded window to re-acquire
+// the same lock before it gives up, so a brief apiserver hiccup doesn't force
+// a pod restart and a cold cache.
+package leaderelector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/klog"
+)
+
+// Elector runs leaderelection.RunOrDie in a loop, retrying re-acquisition of
+// the configured lock for RetryDuration after a lease is lost before giving up.
+type Elector struct {
+	// Config is used verbatim for every (re-)acquisition attempt, Callbacks excluded.
+	Config leaderelection.LeaderElectionConfig
+	// RetryDuration bounds how long Run keeps retrying after losing the lease
+	// before returning control to the caller.
+	RetryDuration time.Duration
+	// OnStarted is called with a fresh, cancellable context each time leadership
+	// is (re-)acquired. Reconciliation should run for as long as this context is alive.
+	OnStarted func(ctx context.Context)
+	// OnStopped is called every time leadership is lost, including during a retry
+	// that eventually succeeds. It should stop reconciliation without tearing down
+	// caches, since leadership may be regained shortly after.
+	OnStopped func()
+}
+
+// NewElector builds an Elector around cfg, ignoring any Callbacks already set on it.
+func NewElector(cfg leaderelection.LeaderElectionConfig, retryDuration time.Duration, onStarted func(ctx context.Context), onStopped func()) *Elector {
+	return &Elector{
+		Config:        cfg,
+		RetryDuration: retryDuration,
+		OnStarted:     onStarted,
+		OnStopped:     onStopped,
+	}
+}
+
+// Run blocks until ctx is cancelled or leadership cannot be re-acquired within
+// RetryDuration of being lost, whichever happens first. In the latter case it
+// returns a non-nil error so the caller can exit non-zero.
+//
+// The first acquisition attempt waits indefinitely, just like plain
+// leaderelection.RunOrDie. Every subsequent loss goes through reacquire, which
+// only bounds how long we wait to become leader again — once re-acquired, the
+// new term runs for as long as ctx stays alive, not for RetryDuration.
+func (e *Elector) Run(ctx context.Context) error {
+	cfg := e.Config
+	cfg.Callbacks = leaderelection.LeaderCallbacks{
+		OnStartedLeading: e.OnStarted,
+		OnStoppedLeading: e.OnStopped,
+	}
+	leaderelection.RunOrDie(ctx, cfg)
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if !e.reacquire(ctx) {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to re-acquire leadership within %s, giving up", e.RetryDuration)
+		}
+	}
+}
+
+// reacquire runs a single leaderelection.RunOrDie call whose context is only
+// cancelled early if leadership isn't acquired within RetryDuration; once
+// OnStartedLeading fires, that cancellation is disarmed, so the resulting term
+// is bounded solely by ctx and ends only on a genuine loss of leadership. It
+// blocks until that term ends and reports whether leadership was ever acquired.
+func (e *Elector) reacquire(ctx context.Context) bool {
+	acquireCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	giveUp := time.AfterFunc(e.RetryDuration, cancel)
+	defer giveUp.Stop()
+
+	acquired := make(chan struct{})
+	cfg := e.Config
+	cfg.Callbacks = leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(leaderCtx context.Context) {
+			giveUp.Stop()
+			close(acquired)
+			e.OnStarted(leaderCtx)
+		},
+		OnStoppedLeading: e.OnStopped,
+	}
+
+	klog.Info("attempting to re-acquire leadership")
+	leaderelection.RunOrDie(acquireCtx, cfg)
+
+	select {
+	case <-acquired:
+		return true
+	default:
+		return false
+	}
+}
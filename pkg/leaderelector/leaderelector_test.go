@@ -0,0 +1,173 @@
+/*
+Copyright 2020 KubeSphere Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelector
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// fakeLock is a minimal in-memory resourcelock.Interface, just enough to drive a
+// real leaderelection.LeaderElector without standing up a fake apiserver.
+type fakeLock struct {
+	identity string
+
+	mu     sync.Mutex
+	record *resourcelock.LeaderElectionRecord
+}
+
+// Get returns the record's JSON encoding as the raw record, like the real locks do:
+// the leader elector only re-reads the holder when the raw bytes change.
+func (f *fakeLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.record == nil {
+		return nil, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "leases"}, "test")
+	}
+	rec := *f.record
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &rec, raw, nil
+}
+
+func (f *fakeLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.record != nil {
+		return apierrors.NewAlreadyExists(schema.GroupResource{Resource: "leases"}, "test")
+	}
+	rec := ler
+	f.record = &rec
+	return nil
+}
+
+func (f *fakeLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rec := ler
+	f.record = &rec
+	return nil
+}
+
+func (f *fakeLock) RecordEvent(string) {}
+
+func (f *fakeLock) Identity() string { return f.identity }
+
+func (f *fakeLock) Describe() string { return "fake lock for leaderelector tests" }
+
+// evict hands the lease to a different holder without touching RenewTime, which is
+// enough to make the current holder's next renewal fail, simulating the kind of
+// transient lease loss Elector is meant to recover from.
+func (f *fakeLock) evict() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.record != nil {
+		f.record.HolderIdentity = "someone-else"
+	}
+}
+
+// TestElectorReacquiredTermNotBoundedByRetryDuration guards against the bug fixed in
+// 6bc89e1: once leadership is regained inside reacquire, the new term must last as
+// long as ctx does, not get cut short when the original RetryDuration window elapses.
+func TestElectorReacquiredTermNotBoundedByRetryDuration(t *testing.T) {
+	lock := &fakeLock{identity: "test"}
+
+	cfg := leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: 300 * time.Millisecond,
+		RenewDeadline: 200 * time.Millisecond,
+		RetryPeriod:   50 * time.Millisecond,
+	}
+	const retryDuration = 500 * time.Millisecond
+
+	var mu sync.Mutex
+	stopCount := 0
+	startedCh := make(chan struct{}, 10)
+	stoppedCh := make(chan struct{}, 10)
+
+	elector := NewElector(cfg, retryDuration,
+		func(ctx context.Context) {
+			startedCh <- struct{}{}
+			<-ctx.Done()
+		},
+		func() {
+			mu.Lock()
+			stopCount++
+			mu.Unlock()
+			stoppedCh <- struct{}{}
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() { runErr <- elector.Run(ctx) }()
+
+	select {
+	case <-startedCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for initial leadership")
+	}
+
+	// Force the held lease to look stolen, so the elector's next renewal fails and
+	// it has to go through reacquire to win it back.
+	lock.evict()
+
+	select {
+	case <-stoppedCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for leadership loss")
+	}
+
+	select {
+	case <-startedCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for leadership to be re-acquired")
+	}
+
+	// retryDuration has now elapsed since the loss. If reacquire still bounded the
+	// new term to that window, OnStopped would fire again on its own around now.
+	time.Sleep(retryDuration + 300*time.Millisecond)
+
+	mu.Lock()
+	got := stopCount
+	mu.Unlock()
+	if got != 1 {
+		t.Fatalf("OnStopped fired %d times, want 1: the re-acquired term was cut short by RetryDuration", got)
+	}
+
+	cancel()
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Fatalf("Run returned %v after ctx was cancelled, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for Run to return after ctx was cancelled")
+	}
+}